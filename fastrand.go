@@ -1,72 +1,179 @@
 // Package fastrand implements a cryptographically secure pseudorandom number
 // generator. The generator is seeded using the system's default entropy
-// source, and thereafter produces random values via repeated hashing. As a
-// result, fastrand can generate randomness much faster than crypto/rand, and
-// generation cannot fail.
+// source, and thereafter produces random values via a fast-key-erasure
+// ChaCha20 stream. As a result, fastrand can generate randomness much faster
+// than crypto/rand, and generation cannot fail.
 package fastrand
 
 import (
 	"crypto/rand"
-	"hash"
 	"io"
 	"math"
 	"math/big"
+	"os"
+	"runtime"
 	"sync"
 	"unsafe"
 
-	"github.com/minio/blake2b-simd"
+	"golang.org/x/crypto/chacha20"
 )
 
-// A randReader produces random values via repeated hashing. The entropy field
-// is the concatenation of an initial seed and a 128-bit counter. Each time
-// the entropy is hashed, the counter is incremented.
-type randReader struct {
-	entropy []byte
-	h       hash.Hash
-	buf     []byte
-	mu      sync.Mutex
+// runtime_procPin and runtime_procUnpin pin and unpin the calling goroutine
+// to its current P, mirroring the linknames sync.Pool uses internally. We
+// use them purely to pick a generator shard local to the current P; the
+// pinned section is too short to cause scheduling problems.
+//
+//go:linkname runtime_procPin sync.runtime_procPin
+func runtime_procPin() int
+
+//go:linkname runtime_procUnpin sync.runtime_procUnpin
+func runtime_procUnpin()
+
+const (
+	// keySize is the size of a ChaCha20 key.
+	keySize = 32
+	// bufSize is the total size of Rand's buffer: a single-use
+	// ChaCha20 key followed by a pool of pre-generated random output.
+	bufSize = 1024 * 64
+)
+
+// A Rand produces random values using a fast-key-erasure ChaCha20 CSPRNG.
+// The first keySize bytes of buf are always the key that will be used to
+// derive the next key and the next pool of output; the remaining bytes are
+// unread output. Output bytes are erased (zeroed) as soon as they're handed
+// out, and the key used to generate a pool is discarded the instant it's
+// been used, so a compromise of the in-memory state cannot be used to
+// recover previously-generated output. A Rand is safe for concurrent use by
+// multiple goroutines.
+type Rand struct {
+	buf [bufSize]byte
+	n   int // number of unread bytes remaining at the tail of buf
+	pid int // pid at the time buf was last (re)seeded
+	mu  sync.Mutex
+}
+
+// refill generates a new key and a new pool of random output by encrypting
+// buf with ChaCha20 using the current key (buf[:keySize]). Since the key is
+// used exactly once, the nonce can safely be all-zero. The first keySize
+// bytes of the result become the next single-use key; the rest become the
+// new pool of output.
+func (r *Rand) refill() {
+	c, err := chacha20.NewUnauthenticatedCipher(r.buf[:keySize], make([]byte, chacha20.NonceSize))
+	if err != nil {
+		panic("fastrand: " + err.Error())
+	}
+	c.XORKeyStream(r.buf[:], r.buf[:])
+	r.n = bufSize - keySize
+}
+
+// reseed discards all buffered state and draws a fresh key from crypto/rand.
+// It's called from Read when the process's pid has changed since we were
+// last seeded: without this, a forked child would carry on handing out
+// whatever was left unread in buf (or, once that drains, deriving its next
+// pool from the same key), and would produce exactly the same output stream
+// as its parent.
+func (r *Rand) reseed(pid int) {
+	for i := range r.buf {
+		r.buf[i] = 0
+	}
+	if _, err := io.ReadFull(rand.Reader, r.buf[:keySize]); err != nil {
+		panic("fastrand: no entropy available")
+	}
+	r.n = 0
+	r.pid = pid
 }
 
 // Read fills b with random data. It always returns len(b), nil.
-func (r *randReader) Read(b []byte) (int, error) {
+func (r *Rand) Read(b []byte) (int, error) {
 	r.mu.Lock()
-	n := 0
-	for n < len(b) {
-		// Increment counter.
-		*(*uint64)(unsafe.Pointer(&r.entropy[0]))++
-		if *(*uint64)(unsafe.Pointer(&r.entropy[0])) == 0 {
-			*(*uint64)(unsafe.Pointer(&r.entropy[8]))++
+	if pid := os.Getpid(); pid != r.pid {
+		// We've forked (or been checkpoint/restored) since we were last
+		// seeded. Reseed now, before handing out a single byte: any output
+		// already sitting unread in buf was derived from pre-fork state and
+		// would otherwise be duplicated verbatim by parent and child alike.
+		r.reseed(pid)
+	}
+	total := len(b)
+	for len(b) > 0 {
+		if r.n == 0 {
+			r.refill()
 		}
-		// Hash the counter + initial seed.
-		r.h.Reset()
-		r.h.Write(r.entropy)
-		r.buf = r.h.Sum(r.buf[:0])
-
-		// Fill out 'b'.
-		n += copy(b[n:], r.buf[:])
+		off := bufSize - r.n
+		c := copy(b, r.buf[off:])
+		for i := off; i < off+c; i++ {
+			r.buf[i] = 0
+		}
+		r.n -= c
+		b = b[c:]
 	}
 	r.mu.Unlock()
-	return n, nil
+	return total, nil
 }
 
-// Reader is a global, shared instance of a cryptographically strong pseudo-
-// random generator. It uses blake2b as its hashing function. Reader is safe
-// for concurrent use by multiple goroutines.
-var Reader = func() *randReader {
-	r := &randReader{h: blake2b.New256()}
-	// Use 64 bytes in case the first 32 aren't completely random.
-	_, err := io.CopyN(r.h, rand.Reader, 64)
-	if err != nil {
-		panic("crypto: no entropy available")
+// newSeededReader returns a Rand seeded from crypto/rand.
+func newSeededReader() *Rand {
+	r := &Rand{pid: os.Getpid()}
+	// Seed the initial key from crypto/rand; it is consumed (and erased) by
+	// the first refill.
+	if _, err := io.ReadFull(rand.Reader, r.buf[:keySize]); err != nil {
+		panic("fastrand: no entropy available")
 	}
-	r.entropy = make([]byte, 16+32) // blake2b produces [32]byte hashes
-	r.h.Sum(r.entropy[16:])
+	// Best-effort: ask the kernel to zero buf in any forked child, so that a
+	// child's memory never holds the parent's key or unread output. The
+	// reseed itself is triggered by the pid check in Read.
+	wipeOnFork(r.buf[:])
 	return r
+}
+
+// Reader is a global, shared instance of a cryptographically strong pseudo-
+// random generator. Reader is safe for concurrent use by multiple
+// goroutines.
+var Reader = newSeededReader()
+
+// NewReader returns a new, independently-seeded generator that is not part
+// of the shared shard pool used by the package-level functions. It's useful
+// for callers that want an isolated instance. Its output is seeded from
+// crypto/rand and is therefore not reproducible; for a deterministic stream
+// (e.g. in tests), use NewReaderFromSeed instead.
+func NewReader() *Rand {
+	return newSeededReader()
+}
+
+// NewReaderFromSeed returns a new, independently-seeded generator whose
+// initial key is read from seed instead of crypto/rand. It exists to give
+// tests a reproducible stream; it is not suitable for production use, since
+// a predictable seed defeats the purpose of a CSPRNG.
+func NewReaderFromSeed(seed io.Reader) (*Rand, error) {
+	r := &Rand{pid: os.Getpid()}
+	if _, err := io.ReadFull(seed, r.buf[:keySize]); err != nil {
+		return nil, err
+	}
+	wipeOnFork(r.buf[:])
+	return r, nil
+}
+
+// shards holds one independently-seeded generator per P, so that Read,
+// Bytes, Intn, and Perm can avoid contending on a single mutex under
+// parallel workloads.
+var shards = func() []*Rand {
+	s := make([]*Rand, runtime.GOMAXPROCS(0))
+	for i := range s {
+		s[i] = newSeededReader()
+	}
+	return s
 }()
 
-// Read is a helper function that calls Reader.Read on b. It always fills b
-// completely.
-func Read(b []byte) { Reader.Read(b) }
+// currentShard returns the generator shard local to the P the calling
+// goroutine is currently running on.
+func currentShard() *Rand {
+	pid := runtime_procPin()
+	runtime_procUnpin()
+	return shards[pid%len(shards)]
+}
+
+// Read is a helper function that fills b with random data, drawing from the
+// shard local to the current P. It always fills b completely.
+func Read(b []byte) { currentShard().Read(b) }
 
 // Bytes is a helper function that returns n bytes of random data.
 func Bytes(n int) []byte {
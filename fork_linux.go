@@ -0,0 +1,15 @@
+//go:build linux
+// +build linux
+
+package fastrand
+
+import "golang.org/x/sys/unix"
+
+// wipeOnFork advises the kernel to zero the pages backing buf in any forked
+// child (MADV_WIPEONFORK, Linux 4.14+). It's a best-effort hardening
+// measure on top of the pid check in Rand.Read: if the kernel is too
+// old to support it, or buf's pages happen to be shared with adjacent
+// allocations, the pid check still catches the fork and reseeds explicitly.
+func wipeOnFork(buf []byte) {
+	_ = unix.Madvise(buf, unix.MADV_WIPEONFORK)
+}
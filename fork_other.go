@@ -0,0 +1,8 @@
+//go:build !linux
+// +build !linux
+
+package fastrand
+
+// wipeOnFork is a no-op on platforms without MADV_WIPEONFORK; the pid check
+// in Rand.Read still detects a fork and reseeds explicitly.
+func wipeOnFork([]byte) {}
@@ -0,0 +1,70 @@
+package fastrand
+
+import (
+	"bytes"
+	"os"
+	"testing"
+)
+
+// TestForkMidBuffer exercises the case a prior version of the pid check
+// missed: a fork happening while buf still has unread output sitting in it
+// (n > 0). Read must reseed before handing out any of that leftover output,
+// not just at the next refill.
+func TestForkMidBuffer(t *testing.T) {
+	parent := newSeededReader()
+
+	// Consume part of the buffer so some pre-fork output remains unread.
+	parent.Read(make([]byte, 8))
+	if parent.n == 0 || parent.n == bufSize-keySize {
+		t.Fatalf("setup: expected partially-drained buffer, got n=%d", parent.n)
+	}
+
+	// Simulate a fork: the child starts with a byte-for-byte copy of the
+	// parent's buf/n, but the OS reports it a different pid. We can't
+	// literally fork(2) the Go runtime in a test, so we copy the relevant
+	// fields by hand (not the whole struct, since Rand embeds a sync.Mutex)
+	// and fake the pid mismatch the same way the real check would observe it.
+	child := &Rand{buf: parent.buf, n: parent.n, pid: os.Getpid() + 1}
+
+	parentOut := make([]byte, 8)
+	childOut := make([]byte, 8)
+	parent.Read(parentOut)
+	child.Read(childOut)
+
+	if bytes.Equal(parentOut, childOut) {
+		t.Fatal("parent and child produced identical output after a simulated mid-buffer fork")
+	}
+	if child.pid != os.Getpid() {
+		t.Fatalf("child pid not resynced: got %d want %d", child.pid, os.Getpid())
+	}
+}
+
+// TestForkAtRefillBoundary covers the simpler case where the fork happens to
+// land exactly when the buffer is empty.
+func TestForkAtRefillBoundary(t *testing.T) {
+	r := newSeededReader()
+	b1 := make([]byte, 8)
+	r.Read(b1)
+	// Drain the rest of the buffer so the next Read would have to refill.
+	r.Read(make([]byte, r.n))
+	if r.n != 0 {
+		t.Fatalf("setup: expected drained buffer, got n=%d", r.n)
+	}
+
+	preBuf := make([]byte, len(r.buf))
+	copy(preBuf, r.buf[:])
+	r.pid = os.Getpid() + 99999
+
+	b2 := make([]byte, 8)
+	r.Read(b2)
+
+	if bytes.Equal(preBuf, r.buf[:]) {
+		t.Fatal("buffer unchanged after simulated fork; reseed did not run")
+	}
+	if r.pid != os.Getpid() {
+		t.Fatalf("pid not resynced: got %d want %d", r.pid, os.Getpid())
+	}
+	if bytes.Equal(b1, b2) {
+		t.Fatal("output repeated across simulated fork boundary")
+	}
+}
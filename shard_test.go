@@ -0,0 +1,37 @@
+package fastrand
+
+import (
+	"sync"
+	"testing"
+)
+
+// TestShardsConcurrent exercises the per-P shard pool under concurrent use;
+// run with -race to catch data races on the shards.
+func TestShardsConcurrent(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := 0; i < 32; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				Bytes(8)
+				Intn(97)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// TestNewReaderIndependent checks that two NewReader instances don't share
+// state: their outputs differ.
+func TestNewReaderIndependent(t *testing.T) {
+	r1 := NewReader()
+	r2 := NewReader()
+	b1 := make([]byte, 16)
+	b2 := make([]byte, 16)
+	r1.Read(b1)
+	r2.Read(b2)
+	if string(b1) == string(b2) {
+		t.Fatal("two independently-seeded readers produced identical output")
+	}
+}
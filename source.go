@@ -0,0 +1,119 @@
+package fastrand
+
+import (
+	"math"
+	"math/rand"
+	"unsafe"
+)
+
+// Source adapts fastrand to the math/rand.Source and math/rand.Source64
+// interfaces, so that it can be used as a drop-in, cryptographically strong
+// replacement for math/rand's default source:
+//
+//	r := rand.New(fastrand.NewSource())
+type Source struct{}
+
+// NewSource returns a Source drawing from fastrand's shared, per-P-sharded
+// generator.
+func NewSource() *Source { return &Source{} }
+
+// Int63 returns a non-negative pseudo-random 63-bit integer.
+func (s *Source) Int63() int64 { return Int63() }
+
+// Uint64 returns a pseudo-random 64-bit value.
+func (s *Source) Uint64() uint64 { return Uint64() }
+
+// Seed is a no-op: Source is always seeded from crypto/rand, and reseeding
+// it from a 63-bit int64 would only make it weaker.
+func (s *Source) Seed(int64) {}
+
+// Uint64 returns a pseudo-random 64-bit value.
+func Uint64() uint64 {
+	b := Bytes(8)
+	return *(*uint64)(unsafe.Pointer(&b[0]))
+}
+
+// Uint64n returns a uniform random value in [0,n). It panics if n == 0.
+func Uint64n(n uint64) uint64 {
+	if n == 0 {
+		panic("fastrand: argument to Uint64n is 0")
+	}
+	// See the NOTE in Intn.
+	max := math.MaxUint64 - math.MaxUint64%n
+	r := Uint64()
+	for r >= max {
+		r = Uint64()
+	}
+	return r % n
+}
+
+// Int31 returns a non-negative pseudo-random 31-bit integer.
+func Int31() int32 { return int32(Uint64() >> 33) }
+
+// Int31n returns a uniform random value in [0,n). It panics if n <= 0.
+func Int31n(n int32) int32 {
+	if n <= 0 {
+		panic("fastrand: argument to Int31n is <= 0")
+	}
+	return int32(Uint64n(uint64(n)))
+}
+
+// Int63 returns a non-negative pseudo-random 63-bit integer.
+func Int63() int64 { return int64(Uint64() >> 1) }
+
+// Int63n returns a uniform random value in [0,n). It panics if n <= 0.
+func Int63n(n int64) int64 {
+	if n <= 0 {
+		panic("fastrand: argument to Int63n is <= 0")
+	}
+	return int64(Uint64n(uint64(n)))
+}
+
+// Float32 returns a pseudo-random number in [0.0,1.0).
+func Float32() float32 {
+again:
+	f := float32(Float64())
+	if f == 1 {
+		goto again
+	}
+	return f
+}
+
+// Float64 returns a pseudo-random number in [0.0,1.0).
+func Float64() float64 {
+again:
+	f := float64(Int63()) / (1 << 63)
+	if f == 1 {
+		goto again
+	}
+	return f
+}
+
+// mathRand wraps a Source in a *rand.Rand so NormFloat64 and ExpFloat64 can
+// reuse math/rand's ziggurat tables. Source is stateless (it just forwards
+// to the already-synchronized shard pool), so sharing one instance across
+// goroutines is safe and avoids an allocation per call.
+var mathRand = rand.New(NewSource())
+
+// NormFloat64 returns a normally distributed float64 in the range
+// [-math.MaxFloat64, +math.MaxFloat64] with standard normal distribution
+// (mean = 0, stddev = 1).
+func NormFloat64() float64 { return mathRand.NormFloat64() }
+
+// ExpFloat64 returns an exponentially distributed float64 in the range
+// (0, +math.MaxFloat64] with an exponential distribution whose rate
+// parameter (lambda) is 1 and whose mean is 1/lambda (1).
+func ExpFloat64() float64 { return mathRand.ExpFloat64() }
+
+// Shuffle pseudo-randomizes the order of elements using the Fisher-Yates
+// algorithm, calling swap(i, j) to swap elements with indexes i and j. It
+// panics if n < 0.
+func Shuffle(n int, swap func(i, j int)) {
+	if n < 0 {
+		panic("fastrand: argument to Shuffle is negative")
+	}
+	for i := n - 1; i > 0; i-- {
+		j := Intn(i + 1)
+		swap(i, j)
+	}
+}
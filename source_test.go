@@ -0,0 +1,87 @@
+package fastrand
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestUint64nRange(t *testing.T) {
+	const n = 97
+	for i := 0; i < 10000; i++ {
+		if v := Uint64n(n); v >= n {
+			t.Fatalf("Uint64n(%d) returned out-of-range value %d", n, v)
+		}
+	}
+}
+
+func TestUint64nPanicsOnZero(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("Uint64n(0) did not panic")
+		}
+	}()
+	Uint64n(0)
+}
+
+func TestInt31nAndInt63nRange(t *testing.T) {
+	for i := 0; i < 10000; i++ {
+		if v := Int31n(50); v < 0 || v >= 50 {
+			t.Fatalf("Int31n(50) returned out-of-range value %d", v)
+		}
+		if v := Int63n(50); v < 0 || v >= 50 {
+			t.Fatalf("Int63n(50) returned out-of-range value %d", v)
+		}
+	}
+}
+
+func TestFloat32And64Range(t *testing.T) {
+	for i := 0; i < 10000; i++ {
+		if f := Float64(); f < 0 || f >= 1 {
+			t.Fatalf("Float64 returned out-of-range value %v", f)
+		}
+		if f := Float32(); f < 0 || f >= 1 {
+			t.Fatalf("Float32 returned out-of-range value %v", f)
+		}
+	}
+}
+
+// TestSourceSatisfiesMathRand checks that Source can actually drive a
+// math/rand.Rand, the whole point of adapting it.
+func TestSourceSatisfiesMathRand(t *testing.T) {
+	var _ rand.Source64 = NewSource()
+	r := rand.New(NewSource())
+	if v := r.Intn(100); v < 0 || v >= 100 {
+		t.Fatalf("rand.New(fastrand.NewSource()).Intn(100) returned %d", v)
+	}
+	_ = r.NormFloat64()
+	_ = r.ExpFloat64()
+}
+
+func TestShuffle(t *testing.T) {
+	s := make([]int, 52)
+	for i := range s {
+		s[i] = i
+	}
+	orig := append([]int(nil), s...)
+	Shuffle(len(s), func(i, j int) { s[i], s[j] = s[j], s[i] })
+
+	seen := make(map[int]bool, len(s))
+	for _, v := range s {
+		if v < 0 || v >= len(s) || seen[v] {
+			t.Fatalf("Shuffle produced an invalid permutation: %v", s)
+		}
+		seen[v] = true
+	}
+	if len(s) == len(orig) {
+		same := true
+		for i := range s {
+			if s[i] != orig[i] {
+				same = false
+				break
+			}
+		}
+		if same {
+			t.Log("warning: Shuffle returned the identity permutation (possible but extremely unlikely)")
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package fastrand
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/hex"
+	"math/big"
+)
+
+// Prime returns a number of the given bit length that is probably (with
+// high probability) a prime number, using fastrand as its source of
+// randomness. It panics if bits < 2.
+func Prime(bits int) *big.Int {
+	p, err := rand.Prime(Reader, bits)
+	if err != nil {
+		panic("fastrand: " + err.Error())
+	}
+	return p
+}
+
+// Hex returns a random hex-encoded string of length n. It panics if n < 0.
+func Hex(n int) string {
+	if n < 0 {
+		panic("fastrand: argument to Hex is negative")
+	}
+	return hex.EncodeToString(Bytes((n + 1) / 2))[:n]
+}
+
+// Base64 returns a random base64-encoded string of length n. It panics if
+// n < 0.
+func Base64(n int) string {
+	if n < 0 {
+		panic("fastrand: argument to Base64 is negative")
+	}
+	return base64.RawStdEncoding.EncodeToString(Bytes((n*6 + 7) / 8))[:n]
+}
+
+// Base32 returns a random base32-encoded string of length n. It panics if
+// n < 0.
+func Base32(n int) string {
+	if n < 0 {
+		panic("fastrand: argument to Base32 is negative")
+	}
+	enc := base32.StdEncoding.WithPadding(base32.NoPadding)
+	return enc.EncodeToString(Bytes((n*5 + 7) / 8))[:n]
+}
+
+// String returns a random string of length n drawn uniformly from charset.
+// It panics if charset is empty or if n < 0.
+func String(n int, charset string) string {
+	if len(charset) == 0 {
+		panic("fastrand: charset is empty")
+	}
+	if n < 0 {
+		panic("fastrand: argument to String is negative")
+	}
+	b := make([]byte, n)
+	for i := range b {
+		// Intn already rejection-samples to avoid modulo bias.
+		b[i] = charset[Intn(len(charset))]
+	}
+	return string(b)
+}
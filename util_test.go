@@ -0,0 +1,84 @@
+package fastrand
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHexLength(t *testing.T) {
+	for _, n := range []int{0, 1, 2, 7, 8, 31, 100} {
+		if s := Hex(n); len(s) != n {
+			t.Fatalf("Hex(%d) returned string of length %d: %q", n, len(s), s)
+		}
+	}
+}
+
+func TestBase64Length(t *testing.T) {
+	for _, n := range []int{0, 1, 2, 7, 8, 31, 100} {
+		if s := Base64(n); len(s) != n {
+			t.Fatalf("Base64(%d) returned string of length %d: %q", n, len(s), s)
+		}
+	}
+}
+
+func TestBase32Length(t *testing.T) {
+	for _, n := range []int{0, 1, 2, 7, 8, 31, 100} {
+		if s := Base32(n); len(s) != n {
+			t.Fatalf("Base32(%d) returned string of length %d: %q", n, len(s), s)
+		}
+	}
+}
+
+func TestStringLengthAndCharset(t *testing.T) {
+	const charset = "ABC123"
+	s := String(200, charset)
+	if len(s) != 200 {
+		t.Fatalf("String returned length %d, want 200", len(s))
+	}
+	for _, c := range s {
+		if !strings.ContainsRune(charset, c) {
+			t.Fatalf("String produced character %q outside charset %q", c, charset)
+		}
+	}
+}
+
+func TestNegativeLengthPanics(t *testing.T) {
+	cases := []struct {
+		name string
+		f    func()
+	}{
+		{"Hex", func() { Hex(-1) }},
+		{"Base64", func() { Base64(-1) }},
+		{"Base32", func() { Base32(-1) }},
+		{"String", func() { String(-1, "ab") }},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Fatalf("%s(-1) did not panic", c.name)
+				}
+			}()
+			c.f()
+		})
+	}
+}
+
+func TestStringEmptyCharsetPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("String with empty charset did not panic")
+		}
+	}()
+	String(5, "")
+}
+
+func TestPrime(t *testing.T) {
+	p := Prime(64)
+	if p.BitLen() != 64 {
+		t.Fatalf("Prime(64) returned a number with bit length %d", p.BitLen())
+	}
+	if !p.ProbablyPrime(20) {
+		t.Fatal("Prime(64) returned a non-prime")
+	}
+}